@@ -0,0 +1,60 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command aepc-gateway runs a standalone grpc-gateway reverse proxy in
+// front of an aepc-generated gRPC service, so the REST URLs implied by
+// its google.api.http annotations are actually reachable.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var (
+	grpcEndpoint = flag.String("grpc-endpoint", "localhost:9090", "The gRPC server to proxy requests to")
+	httpPort     = flag.Int("http-port", 8081, "The port to serve the REST gateway on")
+	swaggerUI    = flag.Bool("swagger-ui", false, "Serve a Swagger UI, backed by --openapi-file, alongside the REST gateway")
+	openapiFile  = flag.String("openapi-file", "openapi.json", "Path to the OpenAPI document to serve when --swagger-ui is set")
+)
+
+func main() {
+	flag.Parse()
+	ctx := context.Background()
+	gatewayMux := runtime.NewServeMux()
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if err := registerHandlers(ctx, gatewayMux, *grpcEndpoint, opts); err != nil {
+		log.Fatalf("failed to register gateway handlers: %v", err)
+	}
+	topMux := http.NewServeMux()
+	if *swaggerUI {
+		if err := serveSwaggerUI(topMux, *openapiFile); err != nil {
+			log.Fatalf("failed to serve swagger UI: %v", err)
+		}
+		log.Printf("swagger UI listening at /swagger-ui/, serving %v", *openapiFile)
+	}
+	topMux.Handle("/", gatewayMux)
+	addr := fmt.Sprintf(":%d", *httpPort)
+	log.Printf("gateway listening at %v, proxying to %v", addr, *grpcEndpoint)
+	if err := http.ListenAndServe(addr, topMux); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
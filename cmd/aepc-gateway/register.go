@@ -0,0 +1,31 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"context"
+
+	bpb "github.com/aep-dev/aepc/example/bookstore/v1/bookstore"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+)
+
+// registerHandlers wires the generated Register<Service>HandlerFromEndpoint
+// functions, produced by writer/gateway alongside the service's
+// *.pb.gw.go file, onto mux. aepc-gateway ships built against the
+// bookstore example; pointing it at another service means swapping
+// this import and call for that service's generated package.
+func registerHandlers(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+	return bpb.RegisterBookstoreHandlerFromEndpoint(ctx, mux, endpoint, opts)
+}
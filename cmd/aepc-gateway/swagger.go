@@ -0,0 +1,33 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/aep-dev/aepc/writer/openapi"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// serveSwaggerUI loads the OpenAPI document written to openapiFile
+// (e.g. by the aepc generator ahead of time) and registers it, plus
+// the vendored Swagger UI bundle, on mux.
+func serveSwaggerUI(mux *http.ServeMux, openapiFile string) error {
+	doc, err := openapi3.NewLoader().LoadFromFile(openapiFile)
+	if err != nil {
+		return fmt.Errorf("unable to load %v: %w", openapiFile, err)
+	}
+	return openapi.ServeSwaggerUI(mux, doc)
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command bookstore-server runs the bookstore example's gRPC server,
+// backed by an in-memory store, and its grpc-gateway REST proxy.
+package main
+
+import (
+	"flag"
+
+	"github.com/aep-dev/aepc/example/service"
+)
+
+var (
+	port        = flag.Int("port", 9090, "The gRPC server port")
+	gatewayPort = flag.Int("gateway-port", 8081, "The port to serve the REST gateway on")
+)
+
+func main() {
+	flag.Parse()
+	go service.StartServer(*port)
+	service.StartGateway(*port, *gatewayPort)
+}
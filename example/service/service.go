@@ -1,3 +1,5 @@
+// Package service starts the bookstore example's gRPC server and its
+// grpc-gateway REST proxy.
 package service
 
 import (
@@ -5,73 +7,46 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/credentials/insecure"
 
 	bpb "github.com/aep-dev/aepc/example/bookstore/v1/bookstore"
-	"google.golang.org/protobuf/proto"
-	"google.golang.org/protobuf/types/known/emptypb"
+	"github.com/aep-dev/aepc/storage"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 )
 
-var bookDatabase map[string]*bpb.Book
-
-type BookstoreServer struct {
-	bpb.UnimplementedBookstoreServer
-}
-
-func NewBookstoreServer() *BookstoreServer {
-	return &BookstoreServer{}
-}
-
-func (BookstoreServer) CreateBook(_ context.Context, r *bpb.CreateBookRequest) (*bpb.Book, error) {
-	book := proto.Clone(r.Resource).(*bpb.Book)
-	if r.Id == "" {
-		r.Id = fmt.Sprintf("%v", len(bookDatabase)+1)
-	}
-	path := fmt.Sprintf("books/%v", r.Id)
-	book.Id = r.Id
-	book.Path = path
-	bookDatabase[path] = book
-	log.Printf("created book %q", path)
-	return book, nil
-}
-
-func (BookstoreServer) ApplyBook(_ context.Context, r *bpb.ApplyBookRequest) (*bpb.Book, error) {
-	log.Printf("applying book request: %v", r)
-	originalResource := bookDatabase[r.Path]
-	book := proto.Clone(r.Resource).(*bpb.Book)
-	book.Id = originalResource.Id
-	book.Path = originalResource.Path
-	bookDatabase[r.Path] = book
-	log.Printf("applied book %q", book.Path)
-	return book, nil
-}
-
-func (BookstoreServer) DeleteBook(_ context.Context, r *bpb.DeleteBookRequest) (*emptypb.Empty, error) {
-	delete(bookDatabase, r.Path)
-	log.Printf("deleted book %q", r.Path)
-	return &emptypb.Empty{}, nil
-}
-
-func (BookstoreServer) ReadBook(_ context.Context, r *bpb.ReadBookRequest) (*bpb.Book, error) {
-	if b, found := bookDatabase[r.Path]; found {
-		return b, nil
-	}
-	return nil, status.Errorf(codes.NotFound, "book %q not found", r.Path)
-}
-
+// StartServer serves the generated BookServer, backed by an in-memory
+// store, on targetPort.
 func StartServer(targetPort int) {
-	bookDatabase = make(map[string]*bpb.Book)
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", targetPort))
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 	s := grpc.NewServer()
-	bpb.RegisterBookstoreServer(s, NewBookstoreServer())
+	bpb.RegisterBookstoreServer(s, bpb.NewBookServer(storage.NewInMemory()))
 	log.Printf("server listening at %v", lis.Addr())
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
 }
+
+// StartGateway dials the gRPC server listening on grpcPort and serves
+// the AEP-mandated REST URLs (e.g. POST /books, GET /books/{id}) on
+// httpPort, using the google.api.http annotations already produced by
+// the generator.
+func StartGateway(grpcPort, httpPort int) {
+	ctx := context.Background()
+	mux := runtime.NewServeMux()
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	endpoint := fmt.Sprintf("localhost:%d", grpcPort)
+	if err := bpb.RegisterBookstoreHandlerFromEndpoint(ctx, mux, endpoint, opts); err != nil {
+		log.Fatalf("failed to register gateway handler: %v", err)
+	}
+	addr := fmt.Sprintf(":%d", httpPort)
+	log.Printf("gateway listening at %v, proxying to %v", addr, endpoint)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filter implements AIP-160 filter expressions: parsing them
+// into an AST and evaluating that AST against a proto.Message via
+// reflection, so generated List methods can support `filter` without
+// each resource hand-writing its own expression parser.
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Expr is a parsed AIP-160 filter expression.
+type Expr interface {
+	// Evaluate reports whether msg matches the expression.
+	Evaluate(msg proto.Message) (bool, error)
+}
+
+// Parse parses an AIP-160 filter expression such as
+// `author = "kerouac" AND NOT (pages < 100 OR draft: true)`.
+func Parse(expression string) (Expr, error) {
+	if strings.TrimSpace(expression) == "" {
+		return trueExpr{}, nil
+	}
+	p := &parser{tokens: tokenize(expression)}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.atEnd() {
+		return nil, fmt.Errorf("filter: unexpected token %q", p.peek())
+	}
+	return expr, nil
+}
+
+type trueExpr struct{}
+
+func (trueExpr) Evaluate(proto.Message) (bool, error) { return true, nil }
+
+type andExpr struct{ left, right Expr }
+
+func (e andExpr) Evaluate(msg proto.Message) (bool, error) {
+	l, err := e.left.Evaluate(msg)
+	if err != nil || !l {
+		return false, err
+	}
+	return e.right.Evaluate(msg)
+}
+
+type orExpr struct{ left, right Expr }
+
+func (e orExpr) Evaluate(msg proto.Message) (bool, error) {
+	l, err := e.left.Evaluate(msg)
+	if err != nil {
+		return false, err
+	}
+	if l {
+		return true, nil
+	}
+	return e.right.Evaluate(msg)
+}
+
+type notExpr struct{ inner Expr }
+
+func (e notExpr) Evaluate(msg proto.Message) (bool, error) {
+	v, err := e.inner.Evaluate(msg)
+	return !v, err
+}
+
+type comparisonOp int
+
+const (
+	opEqual comparisonOp = iota
+	opNotEqual
+	opLessThan
+	opLessOrEqual
+	opGreaterThan
+	opGreaterOrEqual
+	opHas
+)
+
+type comparisonExpr struct {
+	field string
+	op    comparisonOp
+	value literal
+}
+
+func (e comparisonExpr) Evaluate(msg proto.Message) (bool, error) {
+	fieldValue, ok, err := fieldByPath(msg, e.field)
+	if err != nil {
+		return false, err
+	}
+	if e.op == opHas {
+		if !ok {
+			return false, nil
+		}
+		return hasValue(fieldValue, e.value), nil
+	}
+	if !ok {
+		return false, nil
+	}
+	cmp, comparable := compare(fieldValue, e.value)
+	if !comparable {
+		return false, nil
+	}
+	switch e.op {
+	case opEqual:
+		return cmp == 0, nil
+	case opNotEqual:
+		return cmp != 0, nil
+	case opLessThan:
+		return cmp < 0, nil
+	case opLessOrEqual:
+		return cmp <= 0, nil
+	case opGreaterThan:
+		return cmp > 0, nil
+	case opGreaterOrEqual:
+		return cmp >= 0, nil
+	default:
+		return false, fmt.Errorf("filter: unknown operator %v", e.op)
+	}
+}
+
+// literal is a parsed right-hand-side value: a string, a float64, a
+// bool, or a time.Time (RFC 3339 timestamp).
+type literal struct {
+	str     *string
+	num     *float64
+	boolean *bool
+	instant *time.Time
+}
+
+func (l literal) String() string {
+	switch {
+	case l.str != nil:
+		return *l.str
+	case l.num != nil:
+		return strconv.FormatFloat(*l.num, 'f', -1, 64)
+	case l.boolean != nil:
+		return strconv.FormatBool(*l.boolean)
+	case l.instant != nil:
+		return l.instant.Format(time.RFC3339)
+	default:
+		return ""
+	}
+}
+
+// fieldByPath walks a dotted field path (e.g. "author.name") through
+// msg via reflection, returning the leaf value.
+func fieldByPath(msg proto.Message, path string) (protoreflect.Value, bool, error) {
+	m := msg.ProtoReflect()
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		fd := m.Descriptor().Fields().ByName(protoreflect.Name(part))
+		if fd == nil {
+			return protoreflect.Value{}, false, nil
+		}
+		if !m.Has(fd) && fd.Cardinality() != protoreflect.Repeated {
+			return protoreflect.Value{}, false, nil
+		}
+		v := m.Get(fd)
+		if i == len(parts)-1 {
+			return v, true, nil
+		}
+		if fd.Kind() != protoreflect.MessageKind {
+			return protoreflect.Value{}, false, fmt.Errorf("filter: %q is not a message field", part)
+		}
+		m = v.Message()
+	}
+	return protoreflect.Value{}, false, nil
+}
+
+func hasValue(v protoreflect.Value, l literal) bool {
+	list, ok := v.Interface().(protoreflect.List)
+	if !ok {
+		cmp, comparable := compare(v, l)
+		return comparable && cmp == 0
+	}
+	for i := 0; i < list.Len(); i++ {
+		cmp, comparable := compare(list.Get(i), l)
+		if comparable && cmp == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// compare returns -1/0/1 comparing v against l, along with whether the
+// two are comparable at all.
+func compare(v protoreflect.Value, l literal) (int, bool) {
+	switch i := v.Interface().(type) {
+	case string:
+		if l.instant != nil {
+			if t, err := time.Parse(time.RFC3339, i); err == nil {
+				return compareTime(t, *l.instant), true
+			}
+		}
+		if l.str == nil {
+			return 0, false
+		}
+		return strings.Compare(i, *l.str), true
+	case bool:
+		if l.boolean == nil {
+			return 0, false
+		}
+		if i == *l.boolean {
+			return 0, true
+		}
+		return 1, true
+	case int32:
+		return compareFloat(float64(i), l)
+	case int64:
+		return compareFloat(float64(i), l)
+	case float32:
+		return compareFloat(float64(i), l)
+	case float64:
+		return compareFloat(i, l)
+	default:
+		return 0, false
+	}
+}
+
+func compareFloat(f float64, l literal) (int, bool) {
+	if l.num == nil {
+		return 0, false
+	}
+	switch {
+	case f < *l.num:
+		return -1, true
+	case f > *l.num:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+func compareTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package filter
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// testMessage is a *descriptorpb.FieldDescriptorProto standing in for
+// a generated resource message: it has a string field (name), an
+// int32 field (number), a bool field (proto3_optional), and a second
+// string field (json_name) holding an RFC 3339 timestamp, so every
+// literal kind filter/parser.go supports has a field to compare
+// against via plain proto reflection.
+func testMessage() *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:           proto.String("kerouac"),
+		Number:         proto.Int32(42),
+		Proto3Optional: proto.Bool(true),
+		JsonName:       proto.String("2023-06-01T00:00:00Z"),
+	}
+}
+
+func evaluate(t *testing.T, expression string) bool {
+	t.Helper()
+	expr, err := Parse(expression)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", expression, err)
+	}
+	ok, err := expr.Evaluate(testMessage())
+	if err != nil {
+		t.Fatalf("Evaluate(%q): %v", expression, err)
+	}
+	return ok
+}
+
+func TestParseEmpty(t *testing.T) {
+	expr, err := Parse("")
+	if err != nil {
+		t.Fatalf("Parse(\"\"): %v", err)
+	}
+	ok, err := expr.Evaluate(testMessage())
+	if err != nil || !ok {
+		t.Errorf("Parse(\"\").Evaluate() = %v, %v, want true, nil", ok, err)
+	}
+}
+
+func TestEvaluateComparisons(t *testing.T) {
+	cases := []struct {
+		expression string
+		want       bool
+	}{
+		{`name = "kerouac"`, true},
+		{`name = "vonnegut"`, false},
+		{`name != "vonnegut"`, true},
+		{`number = 42`, true},
+		{`number < 42`, false},
+		{`number <= 42`, true},
+		{`number > 10`, true},
+		{`number >= 42`, true},
+		{`proto3_optional: true`, true},
+		{`json_name > "2020-01-01T00:00:00Z"`, true},
+		{`json_name < "2020-01-01T00:00:00Z"`, false},
+		{`missing_field = "x"`, false},
+	}
+	for _, c := range cases {
+		if got := evaluate(t, c.expression); got != c.want {
+			t.Errorf("evaluate(%q) = %v, want %v", c.expression, got, c.want)
+		}
+	}
+}
+
+func TestEvaluateBooleanOperators(t *testing.T) {
+	cases := []struct {
+		expression string
+		want       bool
+	}{
+		{`name = "kerouac" AND number = 42`, true},
+		{`name = "kerouac" AND number = 1`, false},
+		{`name = "vonnegut" OR number = 42`, true},
+		{`name = "vonnegut" OR number = 1`, false},
+		{`NOT name = "vonnegut"`, true},
+		{`NOT (name = "kerouac" AND number = 42)`, false},
+		{`name = "kerouac" AND NOT (number = 1 OR proto3_optional: false)`, true},
+	}
+	for _, c := range cases {
+		if got := evaluate(t, c.expression); got != c.want {
+			t.Errorf("evaluate(%q) = %v, want %v", c.expression, got, c.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []string{
+		`name =`,
+		`= "kerouac"`,
+		`(name = "kerouac"`,
+		`name = "kerouac" AND`,
+		`name ? "kerouac"`,
+	}
+	for _, expression := range cases {
+		if _, err := Parse(expression); err == nil {
+			t.Errorf("Parse(%q) = nil error, want an error", expression)
+		}
+	}
+}
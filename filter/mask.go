@@ -0,0 +1,46 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package filter
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// MaskFilter clears every top-level field of msg that isn't named by
+// the first segment of a path in mask, returning a new message so the
+// original is left untouched. A nil or empty mask is a no-op clone of
+// msg. A nested path such as "author.name" keeps the whole "author"
+// field; fields inside it are not trimmed.
+func MaskFilter(msg proto.Message, mask *fieldmaskpb.FieldMask) proto.Message {
+	clone := proto.Clone(msg)
+	if mask == nil || len(mask.GetPaths()) == 0 {
+		return clone
+	}
+	kept := map[string]bool{}
+	for _, path := range mask.GetPaths() {
+		kept[strings.SplitN(path, ".", 2)[0]] = true
+	}
+	m := clone.ProtoReflect()
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if !kept[string(fd.Name())] {
+			m.Clear(fd)
+		}
+		return true
+	})
+	return clone
+}
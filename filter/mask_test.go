@@ -0,0 +1,46 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package filter
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestMaskFilterKeepsOnlyNamedFields(t *testing.T) {
+	msg := testMessage()
+	masked := MaskFilter(msg, &fieldmaskpb.FieldMask{Paths: []string{"name"}}).(*descriptorpb.FieldDescriptorProto)
+	if masked.GetName() != msg.GetName() {
+		t.Errorf("masked.GetName() = %q, want %q", masked.GetName(), msg.GetName())
+	}
+	if masked.Number != nil {
+		t.Errorf("masked.Number = %v, want cleared", masked.GetNumber())
+	}
+	if msg.Number == nil {
+		t.Errorf("MaskFilter mutated the original message's Number field")
+	}
+}
+
+func TestMaskFilterNilOrEmptyIsCloneOnly(t *testing.T) {
+	msg := testMessage()
+	for _, mask := range []*fieldmaskpb.FieldMask{nil, {}} {
+		masked := MaskFilter(msg, mask)
+		if !proto.Equal(masked, msg) {
+			t.Errorf("MaskFilter(msg, %v) = %v, want a clone equal to %v", mask, masked, msg)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package filter
+
+import (
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type orderByTerm struct {
+	field string
+	desc  bool
+}
+
+// OrderBy sorts messages in place according to an AIP-132 order_by
+// expression: a comma-separated list of field references, each
+// optionally suffixed with " desc".
+func OrderBy(messages []proto.Message, orderBy string) error {
+	orderBy = strings.TrimSpace(orderBy)
+	if orderBy == "" {
+		return nil
+	}
+	var terms []orderByTerm
+	for _, part := range strings.Split(orderBy, ",") {
+		fields := strings.Fields(strings.TrimSpace(part))
+		if len(fields) == 0 {
+			continue
+		}
+		term := orderByTerm{field: fields[0]}
+		if len(fields) > 1 && strings.EqualFold(fields[1], "desc") {
+			term.desc = true
+		}
+		terms = append(terms, term)
+	}
+
+	var sortErr error
+	sort.SliceStable(messages, func(i, j int) bool {
+		for _, term := range terms {
+			vi, okI, err := fieldByPath(messages[i], term.field)
+			if err != nil {
+				sortErr = err
+				return false
+			}
+			vj, okJ, err := fieldByPath(messages[j], term.field)
+			if err != nil {
+				sortErr = err
+				return false
+			}
+			if !okI || !okJ {
+				continue
+			}
+			cmp, comparable := compareValues(vi, vj)
+			if !comparable || cmp == 0 {
+				continue
+			}
+			if term.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+	return sortErr
+}
+
+func compareValues(a, b interface{ Interface() interface{} }) (int, bool) {
+	av, bv := a.Interface(), b.Interface()
+	switch x := av.(type) {
+	case string:
+		y, ok := bv.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	case int32:
+		y, ok := bv.(int32)
+		if !ok {
+			return 0, false
+		}
+		return compareOrdered(x, y), true
+	case int64:
+		y, ok := bv.(int64)
+		if !ok {
+			return 0, false
+		}
+		return compareOrdered(x, y), true
+	case float32:
+		y, ok := bv.(float32)
+		if !ok {
+			return 0, false
+		}
+		return compareOrdered(x, y), true
+	case float64:
+		y, ok := bv.(float64)
+		if !ok {
+			return 0, false
+		}
+		return compareOrdered(x, y), true
+	case bool:
+		y, ok := bv.(bool)
+		if !ok || x == y {
+			return 0, true
+		}
+		if x {
+			return 1, true
+		}
+		return -1, true
+	default:
+		return 0, false
+	}
+}
+
+func compareOrdered[T int32 | int64 | float32 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
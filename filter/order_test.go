@@ -0,0 +1,78 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package filter
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func fieldDesc(name string, number int32) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{Name: proto.String(name), Number: proto.Int32(number)}
+}
+
+func names(messages []proto.Message) []string {
+	out := make([]string, len(messages))
+	for i, msg := range messages {
+		out[i] = msg.(*descriptorpb.FieldDescriptorProto).GetName()
+	}
+	return out
+}
+
+func TestOrderByAscending(t *testing.T) {
+	messages := []proto.Message{fieldDesc("c", 3), fieldDesc("a", 1), fieldDesc("b", 2)}
+	if err := OrderBy(messages, "number"); err != nil {
+		t.Fatalf("OrderBy: %v", err)
+	}
+	got := names(messages)
+	want := []string{"a", "b", "c"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("OrderBy ascending = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestOrderByDescending(t *testing.T) {
+	messages := []proto.Message{fieldDesc("c", 3), fieldDesc("a", 1), fieldDesc("b", 2)}
+	if err := OrderBy(messages, "number desc"); err != nil {
+		t.Fatalf("OrderBy: %v", err)
+	}
+	got := names(messages)
+	want := []string{"c", "b", "a"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("OrderBy descending = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestOrderByEmptyIsNoop(t *testing.T) {
+	messages := []proto.Message{fieldDesc("c", 3), fieldDesc("a", 1)}
+	if err := OrderBy(messages, ""); err != nil {
+		t.Fatalf("OrderBy: %v", err)
+	}
+	got := names(messages)
+	want := []string{"c", "a"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("OrderBy(\"\") reordered messages: got %v, want %v", got, want)
+			break
+		}
+	}
+}
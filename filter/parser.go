@@ -0,0 +1,272 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenField tokenKind = iota
+	tokenString
+	tokenNumber
+	tokenOp
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize splits an AIP-160 expression into tokens: quoted strings,
+// numbers, field paths (dotted identifiers), the comparison operators
+// `= != < <= > >= :`, parens, and the AND/OR/NOT keywords.
+func tokenize(expr string) []token {
+	var tokens []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokenLParen, "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenRParen, ")"})
+			i++
+		case c == '"':
+			j := i + 1
+			var sb strings.Builder
+			for j < len(runes) && runes[j] != '"' {
+				sb.WriteRune(runes[j])
+				j++
+			}
+			tokens = append(tokens, token{tokenString, sb.String()})
+			i = j + 1
+		case c == '!' && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, token{tokenOp, "!="})
+			i += 2
+		case c == '<' && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, token{tokenOp, "<="})
+			i += 2
+		case c == '>' && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, token{tokenOp, ">="})
+			i += 2
+		case c == '=' || c == '<' || c == '>' || c == ':':
+			tokens = append(tokens, token{tokenOp, string(c)})
+			i++
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && runes[j] != '(' && runes[j] != ')' &&
+				!strings.ContainsRune("=<>!:", runes[j]) {
+				j++
+			}
+			if j == i {
+				// A lone '!' starts no operator; take it as a one-rune
+				// word so the parser rejects it instead of looping.
+				j++
+			}
+			word := string(runes[i:j])
+			switch strings.ToUpper(word) {
+			case "AND":
+				tokens = append(tokens, token{tokenAnd, word})
+			case "OR":
+				tokens = append(tokens, token{tokenOr, word})
+			case "NOT":
+				tokens = append(tokens, token{tokenNot, word})
+			default:
+				if _, err := strconv.ParseFloat(word, 64); err == nil {
+					tokens = append(tokens, token{tokenNumber, word})
+				} else {
+					tokens = append(tokens, token{tokenField, word})
+				}
+			}
+			i = j
+		}
+	}
+	return tokens
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) atEnd() bool { return p.pos >= len(p.tokens) }
+
+func (p *parser) peek() token {
+	if p.atEnd() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// parseOr parses `<and> (OR <and>)*`.
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for !p.atEnd() && p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+// parseAnd parses `<unary> (AND <unary>)*`.
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for !p.atEnd() && p.peek().kind == tokenAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+// parseUnary parses an optional leading NOT, then a primary
+// expression.
+func (p *parser) parseUnary() (Expr, error) {
+	if !p.atEnd() && p.peek().kind == tokenNot {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a parenthesized expression or a comparison.
+func (p *parser) parsePrimary() (Expr, error) {
+	if !p.atEnd() && p.peek().kind == tokenLParen {
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.atEnd() || p.peek().kind != tokenRParen {
+			return nil, fmt.Errorf("filter: expected closing paren")
+		}
+		p.next()
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses `<field> <op> <value>`.
+func (p *parser) parseComparison() (Expr, error) {
+	if p.atEnd() || p.peek().kind != tokenField {
+		return nil, fmt.Errorf("filter: expected field path, got %q", p.peek().text)
+	}
+	field := p.next().text
+	if p.atEnd() || p.peek().kind != tokenOp {
+		return nil, fmt.Errorf("filter: expected comparison operator after %q", field)
+	}
+	opTok := p.next().text
+	op, err := parseOp(opTok)
+	if err != nil {
+		return nil, err
+	}
+	if p.atEnd() {
+		return nil, fmt.Errorf("filter: expected value after operator %q", opTok)
+	}
+	valueTok := p.next()
+	value, err := parseLiteral(valueTok)
+	if err != nil {
+		return nil, err
+	}
+	return comparisonExpr{field: field, op: op, value: value}, nil
+}
+
+func parseOp(s string) (comparisonOp, error) {
+	switch s {
+	case "=":
+		return opEqual, nil
+	case "!=":
+		return opNotEqual, nil
+	case "<":
+		return opLessThan, nil
+	case "<=":
+		return opLessOrEqual, nil
+	case ">":
+		return opGreaterThan, nil
+	case ">=":
+		return opGreaterOrEqual, nil
+	case ":":
+		return opHas, nil
+	default:
+		return 0, fmt.Errorf("filter: unknown operator %q", s)
+	}
+}
+
+func parseLiteral(t token) (literal, error) {
+	switch t.kind {
+	case tokenString:
+		s := t.text
+		if ts, err := time.Parse(time.RFC3339, s); err == nil {
+			return literal{str: &s, instant: &ts}, nil
+		}
+		return literal{str: &s}, nil
+	case tokenNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return literal{}, fmt.Errorf("filter: invalid number %q: %w", t.text, err)
+		}
+		return literal{num: &n}, nil
+	case tokenField:
+		switch strings.ToLower(t.text) {
+		case "true":
+			b := true
+			return literal{boolean: &b}, nil
+		case "false":
+			b := false
+			return literal{boolean: &b}, nil
+		}
+		s := t.text
+		return literal{str: &s}, nil
+	default:
+		return literal{}, fmt.Errorf("filter: unexpected value token %q", t.text)
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package swaggerui embeds a static Swagger UI bundle so that aepc can
+// serve a browsable REST view of a generated OpenAPI document without a
+// network dependency at runtime.
+package swaggerui
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:generate ./fetch.sh
+
+//go:embed all:dist
+var distFS embed.FS
+
+// FS returns the vendored Swagger UI bundle, rooted at its static
+// assets, ready to be served with http.FileServer.
+func FS() (fs.FS, error) {
+	return fs.Sub(distFS, "dist")
+}
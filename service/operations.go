@@ -0,0 +1,151 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package service holds runtime helpers shared by aepc-generated
+// servers, starting with the google.longrunning.Operations
+// implementation that methods marked long_running point clients at.
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/genproto/googleapis/longrunning"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// OperationsStore tracks in-flight and completed long-running
+// operations in memory, keyed by operation name.
+type OperationsStore struct {
+	mu         sync.Mutex
+	operations map[string]*longrunning.Operation
+	cancel     map[string]context.CancelFunc
+	counter    int
+}
+
+// NewOperationsStore returns an empty OperationsStore.
+func NewOperationsStore() *OperationsStore {
+	return &OperationsStore{
+		operations: make(map[string]*longrunning.Operation),
+		cancel:     make(map[string]context.CancelFunc),
+	}
+}
+
+// cloneOperation returns a copy of op, so a caller can't observe a torn
+// read while Start's background goroutine is still writing to the
+// version stored in s.operations.
+func cloneOperation(op *longrunning.Operation) *longrunning.Operation {
+	return proto.Clone(op).(*longrunning.Operation)
+}
+
+// Start registers a new operation and runs fn in a goroutine, storing
+// whatever message or error it returns as the operation's result once
+// fn completes.
+func (s *OperationsStore) Start(ctx context.Context, metadata proto.Message, fn func(ctx context.Context) (proto.Message, error)) (*longrunning.Operation, error) {
+	s.mu.Lock()
+	s.counter++
+	name := fmt.Sprintf("operations/%v", s.counter)
+	runCtx, cancel := context.WithCancel(ctx)
+	op := &longrunning.Operation{Name: name}
+	if metadata != nil {
+		anyMetadata, err := anypb.New(metadata)
+		if err != nil {
+			s.mu.Unlock()
+			cancel()
+			return nil, fmt.Errorf("unable to pack operation metadata: %w", err)
+		}
+		op.Metadata = anyMetadata
+	}
+	s.operations[name] = op
+	s.cancel[name] = cancel
+	result := cloneOperation(op)
+	s.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		result, err := fn(runCtx)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		op, ok := s.operations[name]
+		if !ok {
+			return
+		}
+		op.Done = true
+		if err != nil {
+			op.Result = &longrunning.Operation_Error{Error: status.Convert(err).Proto()}
+			return
+		}
+		anyResult, packErr := anypb.New(result)
+		if packErr != nil {
+			op.Result = &longrunning.Operation_Error{Error: status.Convert(packErr).Proto()}
+			return
+		}
+		op.Result = &longrunning.Operation_Response{Response: anyResult}
+	}()
+	return result, nil
+}
+
+// GetOperation implements the standard Operations.GetOperation RPC.
+func (s *OperationsStore) GetOperation(_ context.Context, req *longrunning.GetOperationRequest) (*longrunning.Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	op, ok := s.operations[req.Name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "operation %q not found", req.Name)
+	}
+	return cloneOperation(op), nil
+}
+
+// ListOperations implements the standard Operations.ListOperations
+// RPC. It does not yet support filtering or pagination.
+func (s *OperationsStore) ListOperations(_ context.Context, _ *longrunning.ListOperationsRequest) (*longrunning.ListOperationsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := &longrunning.ListOperationsResponse{}
+	for _, op := range s.operations {
+		resp.Operations = append(resp.Operations, cloneOperation(op))
+	}
+	return resp, nil
+}
+
+// CancelOperation implements the standard Operations.CancelOperation
+// RPC by cancelling the context passed to the operation's goroutine.
+func (s *OperationsStore) CancelOperation(_ context.Context, req *longrunning.CancelOperationRequest) (*emptypb.Empty, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cancel, ok := s.cancel[req.Name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "operation %q not found", req.Name)
+	}
+	cancel()
+	return &emptypb.Empty{}, nil
+}
+
+// DeleteOperation implements the standard Operations.DeleteOperation
+// RPC.
+func (s *OperationsStore) DeleteOperation(_ context.Context, req *longrunning.DeleteOperationRequest) (*emptypb.Empty, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.operations[req.Name]; !ok {
+		return nil, status.Errorf(codes.NotFound, "operation %q not found", req.Name)
+	}
+	delete(s.operations, req.Name)
+	delete(s.cancel, req.Name)
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/longrunning"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// awaitDone polls GetOperation until it reports done, failing the test
+// if that doesn't happen within a reasonable time.
+func awaitDone(t *testing.T, s *OperationsStore, name string) *longrunning.Operation {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		op, err := s.GetOperation(context.Background(), &longrunning.GetOperationRequest{Name: name})
+		if err != nil {
+			t.Fatalf("GetOperation: %v", err)
+		}
+		if op.Done {
+			return op
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("operation %v did not complete in time", name)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStartAndGetOperationSucceeds(t *testing.T) {
+	s := NewOperationsStore()
+	result := wrapperspb.String("done")
+	op, err := s.Start(context.Background(), nil, func(context.Context) (proto.Message, error) {
+		return result, nil
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	done := awaitDone(t, s, op.Name)
+	resp := done.GetResponse()
+	if resp == nil {
+		t.Fatalf("done.GetResponse() = nil, want a packed %v", result)
+	}
+	var got wrapperspb.StringValue
+	if err := resp.UnmarshalTo(&got); err != nil {
+		t.Fatalf("unmarshaling response: %v", err)
+	}
+	if got.Value != result.Value {
+		t.Errorf("got.Value = %q, want %q", got.Value, result.Value)
+	}
+}
+
+func TestStartPropagatesFnError(t *testing.T) {
+	s := NewOperationsStore()
+	wantErr := status.Error(codes.InvalidArgument, "bad input")
+	op, err := s.Start(context.Background(), nil, func(context.Context) (proto.Message, error) {
+		return nil, wantErr
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	done := awaitDone(t, s, op.Name)
+	opErr := done.GetError()
+	if opErr == nil {
+		t.Fatalf("done.GetError() = nil, want %v", wantErr)
+	}
+	if codes.Code(opErr.Code) != codes.InvalidArgument {
+		t.Errorf("opErr.Code = %v, want %v", codes.Code(opErr.Code), codes.InvalidArgument)
+	}
+}
+
+func TestGetOperationNotFound(t *testing.T) {
+	s := NewOperationsStore()
+	_, err := s.GetOperation(context.Background(), &longrunning.GetOperationRequest{Name: "operations/missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetOperation err = %v, want codes.NotFound", err)
+	}
+}
+
+func TestCancelOperationCancelsContext(t *testing.T) {
+	s := NewOperationsStore()
+	started := make(chan struct{})
+	canceled := make(chan error, 1)
+	op, err := s.Start(context.Background(), nil, func(ctx context.Context) (proto.Message, error) {
+		close(started)
+		<-ctx.Done()
+		canceled <- ctx.Err()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	<-started
+	if _, err := s.CancelOperation(context.Background(), &longrunning.CancelOperationRequest{Name: op.Name}); err != nil {
+		t.Fatalf("CancelOperation: %v", err)
+	}
+	select {
+	case err := <-canceled:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("ctx.Err() = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("fn's context was never canceled")
+	}
+}
+
+func TestDeleteOperationRemovesIt(t *testing.T) {
+	s := NewOperationsStore()
+	op, err := s.Start(context.Background(), nil, func(context.Context) (proto.Message, error) {
+		return wrapperspb.String("done"), nil
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	awaitDone(t, s, op.Name)
+	if _, err := s.DeleteOperation(context.Background(), &longrunning.DeleteOperationRequest{Name: op.Name}); err != nil {
+		t.Fatalf("DeleteOperation: %v", err)
+	}
+	if _, err := s.GetOperation(context.Background(), &longrunning.GetOperationRequest{Name: op.Name}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetOperation after delete err = %v, want codes.NotFound", err)
+	}
+}
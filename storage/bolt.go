@@ -0,0 +1,135 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package storage
+
+import (
+	"sort"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// BoltDB is a Storage backed by a single bolt bucket, keyed by
+// resource path and storing the wire-encoded proto.Message.
+type BoltDB struct {
+	db         *bolt.DB
+	bucket     []byte
+	newMessage func() proto.Message
+}
+
+// NewBoltDB returns a Storage backed by db, storing messages produced
+// by newMessage in the named bucket, creating it if necessary.
+func NewBoltDB(db *bolt.DB, bucket string, newMessage func() proto.Message) (*BoltDB, error) {
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return &BoltDB{db: db, bucket: []byte(bucket), newMessage: newMessage}, nil
+}
+
+func (s *BoltDB) Get(path string) (proto.Message, error) {
+	msg := s.newMessage()
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(s.bucket).Get([]byte(path))
+		if v == nil {
+			return status.Errorf(codes.NotFound, "resource %q not found", path)
+		}
+		return proto.Unmarshal(v, msg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (s *BoltDB) Put(path string, msg proto.Message) error {
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Put([]byte(path), b)
+	})
+}
+
+func (s *BoltDB) Delete(path string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Delete([]byte(path))
+	})
+}
+
+func (s *BoltDB) List(parent, pageToken string, pageSize int32) ([]proto.Message, string, error) {
+	prefix := parent
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	var paths []string
+	var values [][]byte
+	if err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(s.bucket).Cursor()
+		for k, v := c.Seek([]byte(prefix)); k != nil && strings.HasPrefix(string(k), prefix); k, v = c.Next() {
+			path := string(k)
+			// Stored paths are "<prefix><plural>/<id>", so a direct
+			// child has exactly one "/" left after trimming prefix;
+			// zero means a malformed key, more than one means a
+			// resource nested under a deeper collection.
+			if strings.Count(strings.TrimPrefix(path, prefix), "/") != 1 {
+				continue
+			}
+			paths = append(paths, path)
+			valueCopy := make([]byte, len(v))
+			copy(valueCopy, v)
+			values = append(values, valueCopy)
+		}
+		return nil
+	}); err != nil {
+		return nil, "", err
+	}
+
+	order := make([]int, len(paths))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return paths[order[i]] < paths[order[j]] })
+
+	start := 0
+	if pageToken != "" {
+		start = sort.Search(len(order), func(i int) bool { return paths[order[i]] > pageToken })
+	}
+	end := len(order)
+	if pageSize > 0 && start+int(pageSize) < end {
+		end = start + int(pageSize)
+	}
+
+	results := make([]proto.Message, 0, end-start)
+	for _, idx := range order[start:end] {
+		msg := s.newMessage()
+		if err := proto.Unmarshal(values[idx], msg); err != nil {
+			return nil, "", err
+		}
+		results = append(results, msg)
+	}
+	nextPageToken := ""
+	if end < len(order) {
+		nextPageToken = paths[order[end-1]]
+	}
+	return results, nextPageToken, nil
+}
+
+var _ Storage = (*BoltDB)(nil)
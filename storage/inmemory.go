@@ -0,0 +1,116 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package storage
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// InMemory is a Storage backed by a plain map, guarded by a mutex. A
+// generated server does not fall back to it on its own: like any other
+// Storage, it is passed to New<Kind>Server explicitly.
+type InMemory struct {
+	mu        sync.Mutex
+	resources map[string]proto.Message
+}
+
+// NewInMemory returns an empty InMemory store.
+func NewInMemory() *InMemory {
+	return &InMemory{resources: make(map[string]proto.Message)}
+}
+
+func (s *InMemory) Get(path string) (proto.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg, ok := s.resources[path]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "resource %q not found", path)
+	}
+	return msg, nil
+}
+
+func (s *InMemory) Put(path string, msg proto.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resources == nil {
+		s.resources = make(map[string]proto.Message)
+	}
+	s.resources[path] = msg
+	return nil
+}
+
+func (s *InMemory) Delete(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.resources, path)
+	return nil
+}
+
+func (s *InMemory) List(parent, pageToken string, pageSize int32) ([]proto.Message, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var paths []string
+	prefix := parent
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	for path := range s.resources {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		// Stored paths are "<prefix><plural>/<id>", so a direct
+		// child has exactly one "/" left after trimming prefix;
+		// zero means a malformed key, more than one means a
+		// resource nested under a deeper collection.
+		if strings.Count(strings.TrimPrefix(path, prefix), "/") != 1 {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	start := 0
+	if pageToken != "" {
+		idx := sort.SearchStrings(paths, pageToken)
+		if idx < len(paths) && paths[idx] == pageToken {
+			idx++
+		}
+		start = idx
+	}
+	if start > len(paths) {
+		start = len(paths)
+	}
+	end := len(paths)
+	if pageSize > 0 && start+int(pageSize) < end {
+		end = start + int(pageSize)
+	}
+	page := paths[start:end]
+	results := make([]proto.Message, 0, len(page))
+	for _, path := range page {
+		results = append(results, s.resources[path])
+	}
+	nextPageToken := ""
+	if end < len(paths) {
+		nextPageToken = paths[end-1]
+	}
+	return results, nextPageToken, nil
+}
+
+var _ Storage = (*InMemory)(nil)
@@ -0,0 +1,79 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package storage
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// TestInMemoryListDirectChildren guards against regressing to a prefix
+// check that treats "<parent>/<plural>/<id>" (the path shape Create
+// actually writes) as not being a direct child of parent.
+func TestInMemoryListDirectChildren(t *testing.T) {
+	s := NewInMemory()
+	put := func(path string) {
+		if err := s.Put(path, wrapperspb.String(path)); err != nil {
+			t.Fatalf("Put(%q): %v", path, err)
+		}
+	}
+
+	// Top-level resource: no parent.
+	put("books/1")
+	put("books/2")
+	results, _, err := s.List("", "", 0)
+	if err != nil {
+		t.Fatalf("List(\"\"): %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("List(\"\") = %d results, want 2", len(results))
+	}
+
+	// Nested resource: path is "<parent>/<plural>/<id>".
+	put("publishers/o-reilly/books/3")
+	put("publishers/o-reilly/books/4")
+	put("publishers/o-reilly/books/3/chapters/1") // grandchild, must be excluded
+
+	results, _, err = s.List("publishers/o-reilly", "", 0)
+	if err != nil {
+		t.Fatalf("List(\"publishers/o-reilly\"): %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("List(\"publishers/o-reilly\") = %d results, want 2", len(results))
+	}
+	for _, msg := range results {
+		path := msg.(*wrapperspb.StringValue).Value
+		if path == "publishers/o-reilly/books/3/chapters/1" {
+			t.Errorf("List(\"publishers/o-reilly\") returned grandchild %v", path)
+		}
+	}
+
+	// Confirm proto.Equal still holds for round-tripped values.
+	got, _, err := s.List("", "", 0)
+	if err != nil {
+		t.Fatalf("List(\"\"): %v", err)
+	}
+	want := wrapperspb.String("books/1")
+	found := false
+	for _, msg := range got {
+		if proto.Equal(msg, want) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("List(\"\") did not include %v", want)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storage defines the persistence interface that
+// writer/server-generated resource servers are written against, plus
+// the in-memory and BoltDB backends that ship with aepc.
+package storage
+
+import "google.golang.org/protobuf/proto"
+
+// Storage is the persistence backend a generated resource server reads
+// and writes through. A path is the resource's AEP path (e.g.
+// "publishers/o-reilly/books/123"); a parent is the path prefix
+// everything under it shares (e.g. "publishers/o-reilly").
+type Storage interface {
+	// Get returns the resource stored at path, or an error satisfying
+	// status.Code(err) == codes.NotFound if nothing is stored there.
+	Get(path string) (proto.Message, error)
+	// Put stores msg at path, creating or overwriting it.
+	Put(path string, msg proto.Message) error
+	// Delete removes whatever is stored at path. Deleting a path that
+	// doesn't exist is not an error.
+	Delete(path string) error
+	// List returns the resources whose path is directly under parent,
+	// starting after pageToken, along with the token of the next
+	// page. pageSize <= 0 means no limit. An empty next-page token
+	// means there are no more results.
+	List(parent, pageToken string, pageSize int32) (results []proto.Message, nextPageToken string, err error)
+}
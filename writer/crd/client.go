@@ -0,0 +1,193 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package crd
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+
+	"github.com/aep-dev/aepc/parser"
+)
+
+// GenerateClient renders a typed Go client for r, following the shape
+// of a code-generated CRD client: a Get/List/Create/Update/Delete/Apply
+// method per RPC that writer/proto.AddResource would have added, a
+// SharedIndexInformer that keeps a local cache of the resource's
+// objects in sync via watch, and a lister that reads that cache — the
+// same client/informer/lister trio client-gen produces for a typed
+// clientset.
+func GenerateClient(r *parser.ParsedResource, group, version string) ([]byte, error) {
+	tmpl, err := template.New("client").Funcs(template.FuncMap{
+		"lower": strings.ToLower,
+	}).Parse(clientTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse client template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct {
+		Kind    string
+		Plural  string
+		Group   string
+		Version string
+	}{
+		Kind:    r.Kind,
+		Plural:  r.Plural,
+		Group:   group,
+		Version: version,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to render client for %v: %w", r.Kind, err)
+	}
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to gofmt generated client for %v: %w", r.Kind, err)
+	}
+	return formatted, nil
+}
+
+const clientTemplate = `// Code generated by aepc. DO NOT EDIT.
+package {{lower .Version}}
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+)
+
+var {{.Kind}}Resource = schema.GroupVersionResource{
+	Group:    "{{.Group}}",
+	Version:  "{{.Version}}",
+	Resource: "{{lower .Plural}}",
+}
+
+// {{.Kind}}Interface matches the RPC methods writer/proto.AddResource
+// would have added for a {{.Kind}}, backed by the Kubernetes API
+// instead of a gRPC service.
+type {{.Kind}}Interface interface {
+	Get(ctx context.Context, namespace, name string) (*unstructured.Unstructured, error)
+	List(ctx context.Context, namespace string) ([]unstructured.Unstructured, error)
+	Create(ctx context.Context, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	Update(ctx context.Context, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	Delete(ctx context.Context, namespace, name string) error
+	Apply(ctx context.Context, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+}
+
+type {{lower .Kind}}Client struct {
+	client dynamic.Interface
+}
+
+// New{{.Kind}}Client returns a typed client for {{.Plural}}, scoped to
+// the {{.Group}}/{{.Version}} API group.
+func New{{.Kind}}Client(client dynamic.Interface) {{.Kind}}Interface {
+	return &{{lower .Kind}}Client{client: client}
+}
+
+func (c *{{lower .Kind}}Client) Get(ctx context.Context, namespace, name string) (*unstructured.Unstructured, error) {
+	return c.client.Resource({{.Kind}}Resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *{{lower .Kind}}Client) List(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	list, err := c.client.Resource({{.Kind}}Resource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func (c *{{lower .Kind}}Client) Create(ctx context.Context, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	return c.client.Resource({{.Kind}}Resource).Namespace(namespace).Create(ctx, obj, metav1.CreateOptions{})
+}
+
+func (c *{{lower .Kind}}Client) Update(ctx context.Context, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	return c.client.Resource({{.Kind}}Resource).Namespace(namespace).Update(ctx, obj, metav1.UpdateOptions{})
+}
+
+func (c *{{lower .Kind}}Client) Delete(ctx context.Context, namespace, name string) error {
+	return c.client.Resource({{.Kind}}Resource).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func (c *{{lower .Kind}}Client) Apply(ctx context.Context, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	return c.client.Resource({{.Kind}}Resource).Namespace(namespace).Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: "aepc"})
+}
+
+// New{{.Kind}}Informer returns a SharedIndexInformer that lists and
+// watches {{.Plural}} in namespace (or every namespace, if empty),
+// resyncing its local cache every resyncPeriod.
+func New{{.Kind}}Informer(client dynamic.Interface, namespace string, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.Resource({{.Kind}}Resource).Namespace(namespace).List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.Resource({{.Kind}}Resource).Namespace(namespace).Watch(context.Background(), options)
+			},
+		},
+		&unstructured.Unstructured{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+}
+
+// {{.Kind}}Lister helps list {{.Plural}} from an informer's local
+// cache, without round-tripping to the API server.
+type {{.Kind}}Lister interface {
+	List(namespace string) ([]*unstructured.Unstructured, error)
+	Get(namespace, name string) (*unstructured.Unstructured, error)
+}
+
+type {{lower .Kind}}Lister struct {
+	indexer cache.Indexer
+}
+
+// New{{.Kind}}Lister returns a {{.Kind}}Lister backed by informer's
+// indexer. Call this after informer has synced.
+func New{{.Kind}}Lister(informer cache.SharedIndexInformer) {{.Kind}}Lister {
+	return &{{lower .Kind}}Lister{indexer: informer.GetIndexer()}
+}
+
+func (l *{{lower .Kind}}Lister) List(namespace string) ([]*unstructured.Unstructured, error) {
+	objs, err := l.indexer.ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		results = append(results, obj.(*unstructured.Unstructured))
+	}
+	return results, nil
+}
+
+func (l *{{lower .Kind}}Lister) Get(namespace, name string) (*unstructured.Unstructured, error) {
+	obj, exists, err := l.indexer.GetByKey(fmt.Sprintf("%v/%v", namespace, name))
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: "{{.Group}}", Resource: "{{lower .Plural}}"}, name)
+	}
+	return obj.(*unstructured.Unstructured), nil
+}
+`
@@ -0,0 +1,40 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package crd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aep-dev/aepc/parser"
+)
+
+func TestGenerateClientIncludesClientInformerAndLister(t *testing.T) {
+	r := &parser.ParsedResource{Kind: "Book", Plural: "books"}
+	out, err := GenerateClient(r, "aep.dev", "v1")
+	if err != nil {
+		t.Fatalf("GenerateClient: %v", err)
+	}
+	src := string(out)
+	for _, want := range []string{
+		"func NewBookClient(client dynamic.Interface) BookInterface",
+		"func NewBookInformer(client dynamic.Interface, namespace string, resyncPeriod time.Duration) cache.SharedIndexInformer",
+		"type BookLister interface",
+		"func NewBookLister(informer cache.SharedIndexInformer) BookLister",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated client is missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crd generates a Kubernetes CustomResourceDefinition for each
+// resource in a parsed AEP schema, so the same definition that drives
+// writer/proto can also drive a Kubernetes controller.
+package crd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aep-dev/aepc/parser"
+	"github.com/aep-dev/aepc/schema"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultGroup is the API group every CRD is generated under. GenerateCRD
+// always uses it; r.Type is not consulted for a group.
+const defaultGroup = "aep.dev"
+
+// GenerateCRD builds a CustomResourceDefinition for r, mapping its
+// fields to an OpenAPI v3 schema the same way
+// writer/proto.GeneratedResourceMessage maps them to proto fields.
+func GenerateCRD(r *parser.ParsedResource, version string) (*apiextensionsv1.CustomResourceDefinition, error) {
+	props, err := schemaPropertiesFor(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate crd schema for %v: %w", r.Kind, err)
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.k8s.io/v1",
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%v.%v", r.Plural, defaultGroup),
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: defaultGroup,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:     r.Kind,
+				Plural:   r.Plural,
+				Singular: strings.ToLower(r.Kind),
+			},
+			Scope: scopeFor(r),
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name:    version,
+					Served:  true,
+					Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type:       "object",
+							Properties: props,
+						},
+					},
+				},
+			},
+		},
+	}
+	return crd, nil
+}
+
+// scopeFor returns Namespaced if r (or any of its ancestors) has a
+// parent, and Cluster otherwise, following the same parent-walk as
+// writer/proto.generateHTTPPath.
+func scopeFor(r *parser.ParsedResource) apiextensionsv1.ResourceScope {
+	if len(r.Parents) > 0 {
+		return apiextensionsv1.NamespaceScoped
+	}
+	return apiextensionsv1.ClusterScoped
+}
+
+func schemaPropertiesFor(r *parser.ParsedResource) (map[string]apiextensionsv1.JSONSchemaProps, error) {
+	props := map[string]apiextensionsv1.JSONSchemaProps{}
+	for _, p := range r.GetFieldsSortedByNumber() {
+		t, err := openAPITypeFor(p.Type)
+		if err != nil {
+			return nil, err
+		}
+		props[p.Name] = apiextensionsv1.JSONSchemaProps{Type: t}
+	}
+	return props, nil
+}
+
+func openAPITypeFor(t schema.Type) (string, error) {
+	switch t {
+	case schema.Type_STRING:
+		return "string", nil
+	case schema.Type_INT32, schema.Type_INT64:
+		return "integer", nil
+	case schema.Type_BOOLEAN:
+		return "boolean", nil
+	case schema.Type_DOUBLE, schema.Type_FLOAT:
+		return "number", nil
+	default:
+		return "", fmt.Errorf("crd schema mapping for type %s not found", t)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package crd
+
+import (
+	"testing"
+
+	"github.com/aep-dev/aepc/parser"
+	"github.com/aep-dev/aepc/schema"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestGenerateCRDClusterScoped(t *testing.T) {
+	r := &parser.ParsedResource{Kind: "Book", Plural: "books"}
+	crd, err := GenerateCRD(r, "v1")
+	if err != nil {
+		t.Fatalf("GenerateCRD: %v", err)
+	}
+	if crd.Spec.Scope != apiextensionsv1.ClusterScoped {
+		t.Errorf("Spec.Scope = %v, want ClusterScoped", crd.Spec.Scope)
+	}
+	if crd.Spec.Names.Kind != "Book" || crd.Spec.Names.Plural != "books" {
+		t.Errorf("Spec.Names = %+v, want Kind=Book Plural=books", crd.Spec.Names)
+	}
+	if crd.Spec.Versions[0].Name != "v1" {
+		t.Errorf("Spec.Versions[0].Name = %v, want v1", crd.Spec.Versions[0].Name)
+	}
+	if crd.ObjectMeta.Name != "books.aep.dev" {
+		t.Errorf("ObjectMeta.Name = %v, want books.aep.dev", crd.ObjectMeta.Name)
+	}
+}
+
+func TestGenerateCRDNamespaceScopedWithParent(t *testing.T) {
+	r := &parser.ParsedResource{
+		Kind:    "Book",
+		Plural:  "books",
+		Parents: []*parser.ParsedResource{{Kind: "Publisher", Plural: "publishers"}},
+	}
+	crd, err := GenerateCRD(r, "v1")
+	if err != nil {
+		t.Fatalf("GenerateCRD: %v", err)
+	}
+	if crd.Spec.Scope != apiextensionsv1.NamespaceScoped {
+		t.Errorf("Spec.Scope = %v, want NamespaceScoped", crd.Spec.Scope)
+	}
+}
+
+func TestOpenAPITypeForMappings(t *testing.T) {
+	cases := []struct {
+		in   schema.Type
+		want string
+	}{
+		{schema.Type_STRING, "string"},
+		{schema.Type_INT32, "integer"},
+		{schema.Type_INT64, "integer"},
+		{schema.Type_BOOLEAN, "boolean"},
+		{schema.Type_DOUBLE, "number"},
+		{schema.Type_FLOAT, "number"},
+	}
+	for _, c := range cases {
+		got, err := openAPITypeFor(c.in)
+		if err != nil {
+			t.Errorf("openAPITypeFor(%v): %v", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("openAPITypeFor(%v) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestOpenAPITypeForUnknownType(t *testing.T) {
+	if _, err := openAPITypeFor(schema.Type(-1)); err == nil {
+		t.Error("openAPITypeFor(-1) = nil error, want an error for an unmapped type")
+	}
+}
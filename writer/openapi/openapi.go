@@ -0,0 +1,266 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package openapi generates an OpenAPI v3 document that mirrors the
+// services produced by the writer/proto package.
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aep-dev/aepc/constants"
+	"github.com/aep-dev/aepc/parser"
+	"github.com/aep-dev/aepc/schema"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// AddResource adds a resource's schemas and paths to an OpenAPI document,
+// mirroring the RPCs that writer/proto.AddResource would add to a
+// generated service.
+func AddResource(r *parser.ParsedResource, doc *openapi3.T) error {
+	resourceSchema, err := generatedResourceSchema(r)
+	if err != nil {
+		return fmt.Errorf("unable to generate resource %v: %w", r.Kind, err)
+	}
+	doc.Components.Schemas[r.Kind] = openapi3.NewSchemaRef("", resourceSchema)
+	if r.Methods == nil {
+		return nil
+	}
+	if r.Methods.Create != nil {
+		addCreate(r, doc)
+	}
+	if r.Methods.Read != nil {
+		addGet(r, doc)
+	}
+	if r.Methods.Update != nil {
+		addUpdate(r, doc)
+	}
+	if r.Methods.Delete != nil {
+		addDelete(r, doc)
+	}
+	if r.Methods.List != nil {
+		addList(r, doc)
+	}
+	if r.Methods.GlobalList != nil {
+		addGlobalList(r, doc)
+	}
+	if r.Methods.Apply != nil {
+		addApply(r, doc)
+	}
+	return nil
+}
+
+// generatedResourceSchema builds the OpenAPI schema for the resource
+// itself, following the same field-by-field mapping as
+// writer/proto.GeneratedResourceMessage.
+func generatedResourceSchema(r *parser.ParsedResource) (*openapi3.Schema, error) {
+	s := openapi3.NewObjectSchema()
+	for _, p := range r.GetFieldsSortedByNumber() {
+		fieldSchema, err := schemaForType(p.Type)
+		if err != nil {
+			return nil, err
+		}
+		s.Properties[p.Name] = openapi3.NewSchemaRef("", fieldSchema)
+	}
+	return s, nil
+}
+
+func schemaForType(t schema.Type) (*openapi3.Schema, error) {
+	switch t {
+	case schema.Type_STRING:
+		return openapi3.NewStringSchema(), nil
+	case schema.Type_INT32:
+		return openapi3.NewInt32Schema(), nil
+	case schema.Type_INT64:
+		return openapi3.NewInt64Schema(), nil
+	case schema.Type_BOOLEAN:
+		return openapi3.NewBoolSchema(), nil
+	case schema.Type_DOUBLE, schema.Type_FLOAT:
+		return openapi3.NewFloat64Schema(), nil
+	default:
+		return nil, fmt.Errorf("openapi mapping for type %s not found", t)
+	}
+}
+
+func addCreate(r *parser.ParsedResource, doc *openapi3.T) {
+	op := openapi3.NewOperation()
+	op.OperationID = "Create" + r.Kind
+	op.Description = fmt.Sprintf("An aep-compliant Create method for %v.", r.Kind)
+	op.RequestBody = openapi3.NewRequestBody().WithJSONSchemaRef(openapi3.NewSchemaRef("#/components/schemas/"+r.Kind, nil))
+	op.AddResponse(200, openapi3.NewResponse().WithJSONSchemaRef(responseSchemaRef(r, doc, r.Methods.Create.LongRunning)))
+	pathItem := pathItemFor(doc, generateParentHTTPPath(r))
+	pathItem.Post = op
+}
+
+func addGet(r *parser.ParsedResource, doc *openapi3.T) {
+	op := openapi3.NewOperation()
+	op.OperationID = "Get" + r.Kind
+	op.Description = fmt.Sprintf("An aep-compliant Get method for %v.", r.Kind)
+	addIDParam(op)
+	op.AddResponse(200, openapi3.NewResponse().WithJSONSchemaRef(openapi3.NewSchemaRef("#/components/schemas/"+r.Kind, nil)))
+	pathItem := pathItemFor(doc, "/"+generateHTTPPath(r))
+	pathItem.Get = op
+}
+
+func addUpdate(r *parser.ParsedResource, doc *openapi3.T) {
+	op := openapi3.NewOperation()
+	op.OperationID = "Update" + r.Kind
+	op.Description = fmt.Sprintf("An aep-compliant Update method for %v.", r.Kind)
+	addIDParam(op)
+	op.AddParameter(openapi3.NewQueryParameter(constants.FIELD_UPDATE_MASK_NAME).
+		WithSchema(openapi3.NewStringSchema()).
+		WithDescription("The update mask for the resource, as a FieldMask."))
+	op.RequestBody = openapi3.NewRequestBody().WithJSONSchemaRef(openapi3.NewSchemaRef("#/components/schemas/"+r.Kind, nil))
+	op.AddResponse(200, openapi3.NewResponse().WithJSONSchemaRef(responseSchemaRef(r, doc, r.Methods.Update.LongRunning)))
+	pathItem := pathItemFor(doc, "/"+generateHTTPPath(r))
+	pathItem.Patch = op
+}
+
+func addDelete(r *parser.ParsedResource, doc *openapi3.T) {
+	op := openapi3.NewOperation()
+	op.OperationID = "Delete" + r.Kind
+	op.Description = fmt.Sprintf("An aep-compliant Delete method for %v.", r.Kind)
+	addIDParam(op)
+	if r.Methods.Delete.LongRunning {
+		op.AddResponse(200, openapi3.NewResponse().WithJSONSchemaRef(operationSchemaRef(doc)))
+	} else {
+		op.AddResponse(200, openapi3.NewResponse())
+	}
+	pathItem := pathItemFor(doc, "/"+generateHTTPPath(r))
+	pathItem.Delete = op
+}
+
+func addApply(r *parser.ParsedResource, doc *openapi3.T) {
+	op := openapi3.NewOperation()
+	op.OperationID = "Apply" + r.Kind
+	op.Description = fmt.Sprintf("An aep-compliant Apply method for %v.", r.Plural)
+	addIDParam(op)
+	op.RequestBody = openapi3.NewRequestBody().WithJSONSchemaRef(openapi3.NewSchemaRef("#/components/schemas/"+r.Kind, nil))
+	op.AddResponse(200, openapi3.NewResponse().WithJSONSchemaRef(responseSchemaRef(r, doc, r.Methods.Apply.LongRunning)))
+	pathItem := pathItemFor(doc, "/"+generateHTTPPath(r))
+	pathItem.Put = op
+}
+
+// addIDParam declares the path-scoped "id" parameter implied by the
+// "{id}" placeholder generateHTTPPath always emits, so the path
+// template and its operations agree per the OpenAPI 3.0 spec.
+func addIDParam(op *openapi3.Operation) {
+	op.AddParameter(openapi3.NewPathParameter("id").
+		WithSchema(openapi3.NewStringSchema()).
+		WithRequired(true).
+		WithDescription("The resource's unique identifier, as the last segment of its path."))
+}
+
+// responseSchemaRef returns a ref to r's own resource schema, or to the
+// shared Operation schema when longRunning is set, mirroring
+// writer/proto.responseTypeFor's swap to google.longrunning.Operation.
+func responseSchemaRef(r *parser.ParsedResource, doc *openapi3.T, longRunning bool) *openapi3.SchemaRef {
+	if longRunning {
+		return operationSchemaRef(doc)
+	}
+	return openapi3.NewSchemaRef("#/components/schemas/"+r.Kind, nil)
+}
+
+// operationSchemaRef ensures doc's components describe
+// google.longrunning.Operation, adding it on first use, and returns a
+// ref to it.
+func operationSchemaRef(doc *openapi3.T) *openapi3.SchemaRef {
+	const name = "Operation"
+	if _, ok := doc.Components.Schemas[name]; !ok {
+		s := openapi3.NewObjectSchema()
+		s.Properties["name"] = openapi3.NewSchemaRef("", openapi3.NewStringSchema())
+		s.Properties["metadata"] = openapi3.NewSchemaRef("", openapi3.NewObjectSchema())
+		s.Properties["done"] = openapi3.NewSchemaRef("", openapi3.NewBoolSchema())
+		s.Properties["response"] = openapi3.NewSchemaRef("", openapi3.NewObjectSchema())
+		doc.Components.Schemas[name] = openapi3.NewSchemaRef("", s)
+	}
+	return openapi3.NewSchemaRef("#/components/schemas/"+name, nil)
+}
+
+func addList(r *parser.ParsedResource, doc *openapi3.T) {
+	op := openapi3.NewOperation()
+	op.OperationID = "List" + r.Kind
+	op.Description = fmt.Sprintf("An aep-compliant List method for %v.", r.Plural)
+	addListParams(op)
+	op.AddResponse(200, openapi3.NewResponse())
+	pathItem := pathItemFor(doc, generateParentHTTPPath(r))
+	pathItem.Get = op
+}
+
+func addGlobalList(r *parser.ParsedResource, doc *openapi3.T) {
+	op := openapi3.NewOperation()
+	op.OperationID = "GlobalList" + r.Kind
+	op.Description = fmt.Sprintf("An aep-compliant GlobalList method for %v.", r.Plural)
+	addListParams(op)
+	op.AddResponse(200, openapi3.NewResponse())
+	pathItem := pathItemFor(doc, fmt.Sprintf("/--/%v", strings.ToLower(r.Kind)))
+	pathItem.Get = op
+}
+
+func addListParams(op *openapi3.Operation) {
+	op.AddParameter(openapi3.NewQueryParameter(constants.FIELD_PAGE_TOKEN_NAME).
+		WithSchema(openapi3.NewStringSchema()).
+		WithDescription("The page token indicating the starting point of the page."))
+	op.AddParameter(openapi3.NewQueryParameter(constants.FIELD_MAX_PAGE_SIZE_NAME).
+		WithSchema(openapi3.NewInt32Schema()).
+		WithDescription("The maximum number of resources to return in a single page."))
+	op.AddParameter(openapi3.NewQueryParameter(constants.FIELD_FILTER_NAME).
+		WithSchema(openapi3.NewStringSchema()).
+		WithDescription("The AIP-160 filter expression used to restrict the results returned."))
+	op.AddParameter(openapi3.NewQueryParameter(constants.FIELD_ORDER_BY_NAME).
+		WithSchema(openapi3.NewStringSchema()).
+		WithDescription("A comma-separated list of fields to order the results by, each optionally suffixed with \" desc\"."))
+	op.AddParameter(openapi3.NewQueryParameter(constants.FIELD_READ_MASK_NAME).
+		WithSchema(openapi3.NewStringSchema()).
+		WithDescription("The subset of fields to return in each result."))
+}
+
+func pathItemFor(doc *openapi3.T, path string) *openapi3.PathItem {
+	if doc.Paths == nil {
+		doc.Paths = openapi3.NewPaths()
+	}
+	if pathItem := doc.Paths.Find(path); pathItem != nil {
+		return pathItem
+	}
+	pathItem := &openapi3.PathItem{}
+	doc.Paths.Set(path, pathItem)
+	return pathItem
+}
+
+// generateHTTPPath mirrors writer/proto.generateHTTPPath, which is
+// unexported and therefore duplicated here rather than shared.
+func generateHTTPPath(r *parser.ParsedResource) string {
+	elements := []string{strings.ToLower(r.Plural)}
+	if len(r.Parents) > 0 {
+		// TODO: handle multiple parents
+		p := r.Parents[0]
+		for p != nil {
+			elements = append([]string{strings.ToLower(p.Plural)}, elements...)
+			if len(p.Parents) == 0 {
+				break
+			}
+		}
+	}
+	return fmt.Sprintf("%v/{id}", strings.Join(elements, "/{id}/"))
+}
+
+// generateParentHTTPPath mirrors writer/proto.generateParentHTTPPath.
+func generateParentHTTPPath(r *parser.ParsedResource) string {
+	parentPath := ""
+	if len(r.Parents) > 0 {
+		parentPath = generateHTTPPath(r.Parents[0]) + "/"
+	}
+	return fmt.Sprintf("/%v%v", parentPath, strings.ToLower(r.Plural))
+}
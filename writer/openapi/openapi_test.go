@@ -0,0 +1,82 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package openapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aep-dev/aepc/parser"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func newDoc() *openapi3.T {
+	return &openapi3.T{
+		OpenAPI:    "3.0.0",
+		Info:       &openapi3.Info{Title: "Test", Version: "1.0.0"},
+		Paths:      openapi3.NewPaths(),
+		Components: openapi3.NewComponents(),
+	}
+}
+
+func TestAddResourceProducesAValidDocument(t *testing.T) {
+	r := &parser.ParsedResource{
+		Kind:   "Book",
+		Plural: "books",
+		Methods: &parser.Methods{
+			Create: &parser.MethodOptions{},
+			Read:   &parser.MethodOptions{},
+			Update: &parser.MethodOptions{},
+			Delete: &parser.MethodOptions{},
+			List:   &parser.MethodOptions{},
+			Apply:  &parser.MethodOptions{},
+		},
+	}
+	doc := newDoc()
+	if err := AddResource(r, doc); err != nil {
+		t.Fatalf("AddResource: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Fatalf("generated document is invalid: %v", err)
+	}
+}
+
+func TestAddResourceDeclaresIDPathParam(t *testing.T) {
+	r := &parser.ParsedResource{
+		Kind:   "Book",
+		Plural: "books",
+		Methods: &parser.Methods{
+			Read:   &parser.MethodOptions{},
+			Update: &parser.MethodOptions{},
+			Delete: &parser.MethodOptions{},
+			Apply:  &parser.MethodOptions{},
+		},
+	}
+	doc := newDoc()
+	if err := AddResource(r, doc); err != nil {
+		t.Fatalf("AddResource: %v", err)
+	}
+	pathItem := doc.Paths.Find("/books/{id}")
+	if pathItem == nil {
+		t.Fatalf("doc.Paths has no entry for /books/{id}")
+	}
+	for _, op := range []*openapi3.Operation{pathItem.Get, pathItem.Patch, pathItem.Delete, pathItem.Put} {
+		if op == nil {
+			continue
+		}
+		if op.Parameters.GetByInAndName("path", "id") == nil {
+			t.Errorf("operation %v is missing the path-scoped id parameter", op.OperationID)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aep-dev/aepc/internal/swaggerui"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ServeSwaggerUI registers doc and the vendored Swagger UI bundle on
+// mux. cmd/aepc-gateway wires this in behind its --swagger-ui flag, so
+// a generated OpenAPI document gets a browsable REST view alongside
+// the proxied gRPC service.
+func ServeSwaggerUI(mux *http.ServeMux, doc *openapi3.T) error {
+	assets, err := swaggerui.FS()
+	if err != nil {
+		return err
+	}
+	mux.Handle("/swagger-ui/", http.StripPrefix("/swagger-ui/", http.FileServer(http.FS(assets))))
+	mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(doc)
+	})
+	return nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/desc/builder"
 	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/genproto/googleapis/longrunning"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
@@ -84,10 +85,82 @@ func AddResource(r *parser.ParsedResource, fb *builder.FileBuilder, sb *builder.
 				return err
 			}
 		}
+
+		if resourceHasLongRunningMethod(r) {
+			if err := addOperationsServiceIfAbsent(fb); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resourceHasLongRunningMethod reports whether any of r's methods are
+// configured to return a google.longrunning.Operation rather than the
+// bare resource or Empty.
+func resourceHasLongRunningMethod(r *parser.ParsedResource) bool {
+	m := r.Methods
+	return (m.Create != nil && m.Create.LongRunning) ||
+		(m.Update != nil && m.Update.LongRunning) ||
+		(m.Delete != nil && m.Delete.LongRunning) ||
+		(m.Apply != nil && m.Apply.LongRunning)
+}
+
+// addOperationsServiceIfAbsent adds a service named Operations to fb's
+// own package, carrying the four standard google.longrunning RPCs
+// (Get/List/Cancel/DeleteOperation), so long-running methods have
+// somewhere to point clients for polling.
+func addOperationsServiceIfAbsent(fb *builder.FileBuilder) error {
+	if fb.GetService(operationsServiceName) != nil {
+		return nil
+	}
+	operationMd, err := desc.LoadMessageDescriptor("google.longrunning.Operation")
+	if err != nil {
+		return fmt.Errorf("unable to load google.longrunning.Operation: %w", err)
+	}
+	emptyMd, err := desc.LoadMessageDescriptor("google.protobuf.Empty")
+	if err != nil {
+		return err
+	}
+	getReqMd, err := desc.LoadMessageDescriptor("google.longrunning.GetOperationRequest")
+	if err != nil {
+		return fmt.Errorf("unable to load google.longrunning.GetOperationRequest: %w", err)
+	}
+	listReqMd, err := desc.LoadMessageDescriptor("google.longrunning.ListOperationsRequest")
+	if err != nil {
+		return fmt.Errorf("unable to load google.longrunning.ListOperationsRequest: %w", err)
 	}
+	listRespMd, err := desc.LoadMessageDescriptor("google.longrunning.ListOperationsResponse")
+	if err != nil {
+		return fmt.Errorf("unable to load google.longrunning.ListOperationsResponse: %w", err)
+	}
+	cancelReqMd, err := desc.LoadMessageDescriptor("google.longrunning.CancelOperationRequest")
+	if err != nil {
+		return fmt.Errorf("unable to load google.longrunning.CancelOperationRequest: %w", err)
+	}
+	deleteReqMd, err := desc.LoadMessageDescriptor("google.longrunning.DeleteOperationRequest")
+	if err != nil {
+		return fmt.Errorf("unable to load google.longrunning.DeleteOperationRequest: %w", err)
+	}
+	opsSb := builder.NewService(operationsServiceName)
+	opsSb.AddMethod(builder.NewMethod("GetOperation",
+		builder.RpcTypeImportedMessage(getReqMd, false),
+		builder.RpcTypeImportedMessage(operationMd, false)))
+	opsSb.AddMethod(builder.NewMethod("ListOperations",
+		builder.RpcTypeImportedMessage(listReqMd, false),
+		builder.RpcTypeImportedMessage(listRespMd, false)))
+	opsSb.AddMethod(builder.NewMethod("CancelOperation",
+		builder.RpcTypeImportedMessage(cancelReqMd, false),
+		builder.RpcTypeImportedMessage(emptyMd, false)))
+	opsSb.AddMethod(builder.NewMethod("DeleteOperation",
+		builder.RpcTypeImportedMessage(deleteReqMd, false),
+		builder.RpcTypeImportedMessage(emptyMd, false)))
+	fb.AddService(opsSb)
 	return nil
 }
 
+const operationsServiceName = "Operations"
+
 // GenerateResourceMesssage adds the resource message.
 func GeneratedResourceMessage(r *parser.ParsedResource) (*builder.MessageBuilder, error) {
 	mb := builder.NewMessage(r.Kind)
@@ -139,9 +212,13 @@ func AddCreate(r *parser.ParsedResource, resourceMb *builder.MessageBuilder, fb
 	addIdField(r, mb)
 	addResourceField(r, resourceMb, mb)
 	fb.AddMessage(mb)
+	respType, err := responseTypeFor(resourceMb, r.Methods.Create.LongRunning)
+	if err != nil {
+		return err
+	}
 	method := builder.NewMethod("Create"+r.Kind,
 		builder.RpcTypeMessage(mb, false),
-		builder.RpcTypeMessage(resourceMb, false),
+		respType,
 	)
 	method.SetComments(builder.Comments{
 		LeadingComment: fmt.Sprintf("An aep-compliant Create method for %v.", r.Kind),
@@ -157,6 +234,11 @@ func AddCreate(r *parser.ParsedResource, resourceMb *builder.MessageBuilder, fb
 	proto.SetExtension(options, annotations.E_MethodSignature, []string{
 		strings.Join([]string{constants.FIELD_PARENT_NAME, strings.ToLower(r.Kind)}, ","),
 	})
+	if r.Methods.Create.LongRunning {
+		if err := addOperationInfo(r, fb, "Create", r.Kind, options); err != nil {
+			return err
+		}
+	}
 	method.SetOptions(options)
 	sb.AddMethod(method)
 	return nil
@@ -211,9 +293,13 @@ func AddUpdate(r *parser.ParsedResource, resourceMb *builder.MessageBuilder, fb
 		}))
 
 	fb.AddMessage(mb)
+	respType, err := responseTypeFor(resourceMb, r.Methods.Update.LongRunning)
+	if err != nil {
+		return err
+	}
 	method := builder.NewMethod("Update"+r.Kind,
 		builder.RpcTypeMessage(mb, false),
-		builder.RpcTypeMessage(resourceMb, false),
+		respType,
 	)
 	method.SetComments(builder.Comments{
 		LeadingComment: fmt.Sprintf("An aep-compliant Update method for %v.", r.Kind),
@@ -228,6 +314,11 @@ func AddUpdate(r *parser.ParsedResource, resourceMb *builder.MessageBuilder, fb
 	proto.SetExtension(options, annotations.E_MethodSignature, []string{
 		strings.Join([]string{strings.ToLower(r.Kind), constants.FIELD_UPDATE_MASK_NAME}, ","),
 	})
+	if r.Methods.Update.LongRunning {
+		if err := addOperationInfo(r, fb, "Update", r.Kind, options); err != nil {
+			return err
+		}
+	}
 	method.SetOptions(options)
 	sb.AddMethod(method)
 	return nil
@@ -246,9 +337,17 @@ func AddDelete(r *parser.ParsedResource, resourceMb *builder.MessageBuilder, fb
 	if err != nil {
 		return err
 	}
+	respType := builder.RpcTypeImportedMessage(emptyMd, false)
+	if r.Methods.Delete.LongRunning {
+		opMd, err := desc.LoadMessageDescriptor("google.longrunning.Operation")
+		if err != nil {
+			return fmt.Errorf("unable to load google.longrunning.Operation: %w", err)
+		}
+		respType = builder.RpcTypeImportedMessage(opMd, false)
+	}
 	method := builder.NewMethod("Delete"+r.Kind,
 		builder.RpcTypeMessage(mb, false),
-		builder.RpcTypeImportedMessage(emptyMd, false),
+		respType,
 	)
 	method.SetComments(builder.Comments{
 		LeadingComment: fmt.Sprintf("An aep-compliant Delete method for %v.", r.Kind),
@@ -262,6 +361,11 @@ func AddDelete(r *parser.ParsedResource, resourceMb *builder.MessageBuilder, fb
 	proto.SetExtension(options, annotations.E_MethodSignature, []string{
 		strings.Join([]string{constants.FIELD_PATH_NAME}, ","),
 	})
+	if r.Methods.Delete.LongRunning {
+		if err := addOperationInfo(r, fb, "Delete", "google.protobuf.Empty", options); err != nil {
+			return err
+		}
+	}
 	method.SetOptions(options)
 	sb.AddMethod(method)
 	return nil
@@ -281,6 +385,11 @@ func AddList(r *parser.ParsedResource, resourceMb *builder.MessageBuilder, fb *b
 		SetComments(builder.Comments{
 			LeadingComment: fmt.Sprintf("The maximum number of resources to return in a single page."),
 		}))
+	addFilterField(reqMb)
+	addOrderByField(reqMb)
+	if err := addReadMaskField(reqMb); err != nil {
+		return err
+	}
 	fb.AddMessage(reqMb)
 	respMb := builder.NewMessage("List" + r.Kind + "Response")
 	respMb.SetComments(builder.Comments{
@@ -319,6 +428,11 @@ func AddGlobalList(r *parser.ParsedResource, resourceMb *builder.MessageBuilder,
 	})
 	addPathField(r, reqMb)
 	addPageToken(r, reqMb)
+	addFilterField(reqMb)
+	addOrderByField(reqMb)
+	if err := addReadMaskField(reqMb); err != nil {
+		return err
+	}
 	fb.AddMessage(reqMb)
 	respMb := builder.NewMessage("GlobalList" + r.Kind + "Response")
 	respMb.SetComments(builder.Comments{
@@ -352,9 +466,13 @@ func AddApply(r *parser.ParsedResource, resourceMb *builder.MessageBuilder, fb *
 	addPathField(r, mb)
 	addResourceField(r, resourceMb, mb)
 	fb.AddMessage(mb)
+	respType, err := responseTypeFor(resourceMb, r.Methods.Apply.LongRunning)
+	if err != nil {
+		return err
+	}
 	method := builder.NewMethod("Apply"+r.Kind,
 		builder.RpcTypeMessage(mb, false),
-		builder.RpcTypeMessage(resourceMb, false),
+		respType,
 	)
 	method.SetComments(builder.Comments{
 		LeadingComment: fmt.Sprintf("An aep-compliant Apply method for %v.", r.Plural),
@@ -367,11 +485,69 @@ func AddApply(r *parser.ParsedResource, resourceMb *builder.MessageBuilder, fb *
 		// TODO: do a conversion to underscores instead.
 		Body: strings.ToLower(r.Kind),
 	})
+	if r.Methods.Apply.LongRunning {
+		if err := addOperationInfo(r, fb, "Apply", r.Kind, options); err != nil {
+			return err
+		}
+	}
 	method.SetOptions(options)
 	sb.AddMethod(method)
 	return nil
 }
 
+// responseTypeFor returns the RPC response type for a method on
+// resourceMb, swapping in google.longrunning.Operation when
+// longRunning is set so the caller must poll Operations instead of
+// getting the resource back directly.
+func responseTypeFor(resourceMb *builder.MessageBuilder, longRunning bool) (*builder.RpcType, error) {
+	if !longRunning {
+		return builder.RpcTypeMessage(resourceMb, false), nil
+	}
+	opMd, err := desc.LoadMessageDescriptor("google.longrunning.Operation")
+	if err != nil {
+		return nil, fmt.Errorf("unable to load google.longrunning.Operation: %w", err)
+	}
+	return builder.RpcTypeImportedMessage(opMd, false), nil
+}
+
+// addOperationInfo adds a <verb><Kind>Metadata message to fb and sets
+// the google.longrunning.operation_info method option on options,
+// pointing it at that metadata and at responseType.
+func addOperationInfo(r *parser.ParsedResource, fb *builder.FileBuilder, verb, responseType string, options *descriptorpb.MethodOptions) error {
+	metadataMb := builder.NewMessage(verb + r.Kind + "Metadata")
+	metadataMb.SetComments(builder.Comments{
+		LeadingComment: fmt.Sprintf("Metadata for the %v%v long-running operation.", verb, r.Kind),
+	})
+	timestampMd, err := desc.LoadMessageDescriptor("google.protobuf.Timestamp")
+	if err != nil {
+		return fmt.Errorf("unable to load google.protobuf.Timestamp: %w", err)
+	}
+	metadataMb.AddField(builder.NewField("create_time", builder.FieldTypeImportedMessage(timestampMd)).SetNumber(1).SetComments(builder.Comments{
+		LeadingComment: "The time the operation was created.",
+	}))
+	metadataMb.AddField(builder.NewField("end_time", builder.FieldTypeImportedMessage(timestampMd)).SetNumber(2).SetComments(builder.Comments{
+		LeadingComment: "The time the operation finished running.",
+	}))
+	metadataMb.AddField(builder.NewField("verb", builder.FieldTypeString()).SetNumber(3).SetComments(builder.Comments{
+		LeadingComment: "The verb executed by the operation.",
+	}))
+	metadataMb.AddField(builder.NewField("requested_cancellation", builder.FieldTypeBool()).SetNumber(4).SetComments(builder.Comments{
+		LeadingComment: "Whether the user has requested cancellation of the operation.",
+	}))
+	metadataMb.AddField(builder.NewField("api_version", builder.FieldTypeString()).SetNumber(5).SetComments(builder.Comments{
+		LeadingComment: "The API version used to start the operation.",
+	}))
+	metadataMb.AddField(builder.NewField("progress_percent", builder.FieldTypeInt32()).SetNumber(6).SetComments(builder.Comments{
+		LeadingComment: "The estimated progress of the operation, as a percentage.",
+	}))
+	fb.AddMessage(metadataMb)
+	proto.SetExtension(options, longrunning.E_OperationInfo, &longrunning.OperationInfo{
+		ResponseType: responseType,
+		MetadataType: metadataMb.GetName(),
+	})
+	return nil
+}
+
 func generateHTTPPath(r *parser.ParsedResource) string {
 	elements := []string{strings.ToLower(r.Plural)}
 	if len(r.Parents) > 0 {
@@ -464,3 +640,36 @@ func addNextPageToken(r *parser.ParsedResource, mb *builder.MessageBuilder) {
 	})
 	mb.AddField(f)
 }
+
+// addFilterField adds the AIP-160 filter field to a List request
+// message.
+func addFilterField(mb *builder.MessageBuilder) {
+	f := builder.NewField(constants.FIELD_FILTER_NAME, builder.FieldTypeString()).SetNumber(constants.FIELD_FILTER_NUMBER).SetComments(builder.Comments{
+		LeadingComment: "The AIP-160 filter expression used to restrict the results returned.",
+	})
+	mb.AddField(f)
+}
+
+// addOrderByField adds the AIP-132 order_by field to a List request
+// message.
+func addOrderByField(mb *builder.MessageBuilder) {
+	f := builder.NewField(constants.FIELD_ORDER_BY_NAME, builder.FieldTypeString()).SetNumber(constants.FIELD_ORDER_BY_NUMBER).SetComments(builder.Comments{
+		LeadingComment: "A comma-separated list of fields to order the results by, each optionally suffixed with \" desc\".",
+	})
+	mb.AddField(f)
+}
+
+// addReadMaskField adds the read_mask field to a List request
+// message, trimming returned resources down to the given paths.
+func addReadMaskField(mb *builder.MessageBuilder) error {
+	fieldMaskDescriptor, err := desc.LoadMessageDescriptorForType(reflect.TypeOf(fieldmaskpb.FieldMask{}))
+	if err != nil {
+		return fmt.Errorf("unable to load field mask descriptor: %w", err)
+	}
+	mb.AddField(builder.NewField(constants.FIELD_READ_MASK_NAME, builder.FieldTypeImportedMessage(fieldMaskDescriptor)).
+		SetNumber(constants.FIELD_READ_MASK_NUMBER).
+		SetComments(builder.Comments{
+			LeadingComment: "The subset of fields to return in each result.",
+		}))
+	return nil
+}
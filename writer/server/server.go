@@ -0,0 +1,416 @@
+// Copyright 2023 Yusuke Fredrick Tsutsumi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package server generates a reference implementation of a resource's
+// RPCs against the storage.Storage interface, so examples no longer
+// need to hand-write the map-keyed-by-path, counter-based id, and
+// proto.Clone-on-write boilerplate that writer/proto already implies.
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+
+	"github.com/aep-dev/aepc/parser"
+)
+
+// Options controls the generated file's package and the import path of
+// the package containing the resource's proto-generated Go types.
+type Options struct {
+	// Package is the package name of the generated file.
+	Package string
+	// MessagePkg is the import path of the package containing the
+	// resource's proto-generated Go types.
+	MessagePkg string
+	// MessageAlias is the import alias used to reference MessagePkg.
+	// Defaults to "pb".
+	MessageAlias string
+	// Service is the gRPC service name the resource's RPCs are
+	// registered on (e.g. "Bookstore"), used to embed the standard
+	// Unimplemented<Service>Server forward-compatibility type.
+	Service string
+}
+
+// Generate renders a <kind>_server.go implementing every method
+// r.Methods declares, backed by a storage.Storage the caller supplies
+// at construction time.
+func Generate(r *parser.ParsedResource, opts Options) ([]byte, error) {
+	if r.Methods == nil {
+		return nil, fmt.Errorf("resource %v declares no methods to generate a server for", r.Kind)
+	}
+	if opts.Service == "" {
+		return nil, fmt.Errorf("Options.Service is required to generate a server for %v", r.Kind)
+	}
+	if opts.MessageAlias == "" {
+		opts.MessageAlias = "pb"
+	}
+	data := struct {
+		Options
+		Kind           string
+		Plural         string
+		HasParent      bool
+		Methods        *parser.Methods
+		HasLongRunning bool
+	}{
+		Options:        opts,
+		Kind:           r.Kind,
+		Plural:         strings.ToLower(r.Plural),
+		HasParent:      len(r.Parents) > 0,
+		Methods:        r.Methods,
+		HasLongRunning: resourceHasLongRunningMethod(r.Methods),
+	}
+
+	tmpl, err := template.New("server").Funcs(template.FuncMap{
+		"lower": strings.ToLower,
+	}).Parse(serverTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse server template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("unable to render server for %v: %w", r.Kind, err)
+	}
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to gofmt generated server for %v: %w", r.Kind, err)
+	}
+	return formatted, nil
+}
+
+// resourceHasLongRunningMethod reports whether any of m's methods are
+// configured to return a google.longrunning.Operation rather than the
+// bare resource or Empty.
+func resourceHasLongRunningMethod(m *parser.Methods) bool {
+	return (m.Create != nil && m.Create.LongRunning) ||
+		(m.Update != nil && m.Update.LongRunning) ||
+		(m.Delete != nil && m.Delete.LongRunning) ||
+		(m.Apply != nil && m.Apply.LongRunning)
+}
+
+const serverTemplate = `// Code generated by aepc. DO NOT EDIT.
+package {{.Package}}
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	{{.MessageAlias}} "{{.MessagePkg}}"
+	"github.com/aep-dev/aepc/filter"
+	{{if .HasLongRunning}}"github.com/aep-dev/aepc/service"
+	{{end}}"github.com/aep-dev/aepc/storage"
+	{{if .HasLongRunning}}"google.golang.org/genproto/googleapis/longrunning"
+	{{end}}"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// {{.Kind}}Server implements the {{.Kind}} resource's RPCs against a
+// pluggable storage.Storage backend, so the backing store can be
+// swapped (in-memory, BoltDB, ...) without touching request handling.
+type {{.Kind}}Server struct {
+	{{.MessageAlias}}.Unimplemented{{.Service}}Server
+
+	Storage storage.Storage
+	{{if .HasLongRunning}}// Operations tracks the long-running methods {{.Kind}} declares.
+	Operations *service.OperationsStore
+	{{end}}
+	nextID atomic.Uint64
+}
+
+// New{{.Kind}}Server returns a {{.Kind}}Server backed by s.
+func New{{.Kind}}Server(s storage.Storage) *{{.Kind}}Server {
+	return &{{.Kind}}Server{Storage: s{{if .HasLongRunning}}, Operations: service.NewOperationsStore(){{end}}}
+}
+
+func {{.Kind | lower}}Path({{if .HasParent}}parent, {{end}}id string) string {
+	{{if .HasParent}}return fmt.Sprintf("%v/{{.Plural}}/%v", strings.TrimSuffix(parent, "/"), id){{else}}return fmt.Sprintf("{{.Plural}}/%v", id){{end}}
+}
+
+{{if .Methods.Create}}
+{{if .Methods.Create.LongRunning}}
+// Create{{.Kind}} validates the parent (if {{.Kind}} is a nested
+// resource) and starts the create asynchronously, returning a
+// google.longrunning.Operation that resolves to the created {{.Kind}}.
+func (s *{{.Kind}}Server) Create{{.Kind}}(ctx context.Context, r *{{.MessageAlias}}.Create{{.Kind}}Request) (*longrunning.Operation, error) {
+	{{if .HasParent}}if r.Parent == "" {
+		return nil, fmt.Errorf("parent is required")
+	}
+	{{end}}id := r.Id
+	if id == "" {
+		id = strconv.FormatUint(s.nextID.Add(1), 10)
+	}
+	path := {{.Kind | lower}}Path({{if .HasParent}}r.Parent, {{end}}id)
+	resource := proto.Clone(r.{{.Kind}}).(*{{.MessageAlias}}.{{.Kind}})
+	resource.Id = id
+	resource.Path = path
+	return s.Operations.Start(ctx, nil, func(context.Context) (proto.Message, error) {
+		if err := s.Storage.Put(path, resource); err != nil {
+			return nil, err
+		}
+		return resource, nil
+	})
+}
+{{else}}
+// Create{{.Kind}} validates the parent (if {{.Kind}} is a nested
+// resource), assigns an id when the caller didn't supply one, and
+// composes the resulting path before storing the resource.
+func (s *{{.Kind}}Server) Create{{.Kind}}(_ context.Context, r *{{.MessageAlias}}.Create{{.Kind}}Request) (*{{.MessageAlias}}.{{.Kind}}, error) {
+	{{if .HasParent}}if r.Parent == "" {
+		return nil, fmt.Errorf("parent is required")
+	}
+	{{end}}id := r.Id
+	if id == "" {
+		id = strconv.FormatUint(s.nextID.Add(1), 10)
+	}
+	path := {{.Kind | lower}}Path({{if .HasParent}}r.Parent, {{end}}id)
+	resource := proto.Clone(r.{{.Kind}}).(*{{.MessageAlias}}.{{.Kind}})
+	resource.Id = id
+	resource.Path = path
+	if err := s.Storage.Put(path, resource); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+{{end}}
+{{end}}
+
+{{if .Methods.Read}}
+func (s *{{.Kind}}Server) Get{{.Kind}}(_ context.Context, r *{{.MessageAlias}}.Get{{.Kind}}Request) (*{{.MessageAlias}}.{{.Kind}}, error) {
+	msg, err := s.Storage.Get(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	return msg.(*{{.MessageAlias}}.{{.Kind}}), nil
+}
+{{end}}
+
+{{if .Methods.Update}}
+{{if .Methods.Update.LongRunning}}
+// Update{{.Kind}} merges r.{{.Kind}} onto the stored resource,
+// restricting the merge to r.UpdateMask's paths when one is set, and
+// starts the write asynchronously behind a google.longrunning.Operation.
+func (s *{{.Kind}}Server) Update{{.Kind}}(ctx context.Context, r *{{.MessageAlias}}.Update{{.Kind}}Request) (*longrunning.Operation, error) {
+	existing, err := s.Storage.Get(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	resource := proto.Clone(existing).(*{{.MessageAlias}}.{{.Kind}})
+	if r.UpdateMask != nil && len(r.UpdateMask.GetPaths()) > 0 {
+		applyUpdateMask(resource, r.{{.Kind}}, r.UpdateMask.GetPaths())
+	} else {
+		proto.Merge(resource, r.{{.Kind}})
+	}
+	resource.Path = r.Path
+	return s.Operations.Start(ctx, nil, func(context.Context) (proto.Message, error) {
+		if err := s.Storage.Put(r.Path, resource); err != nil {
+			return nil, err
+		}
+		return resource, nil
+	})
+}
+{{else}}
+// Update{{.Kind}} merges r.{{.Kind}} onto the stored resource,
+// restricting the merge to r.UpdateMask's paths when one is set.
+func (s *{{.Kind}}Server) Update{{.Kind}}(_ context.Context, r *{{.MessageAlias}}.Update{{.Kind}}Request) (*{{.MessageAlias}}.{{.Kind}}, error) {
+	existing, err := s.Storage.Get(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	resource := proto.Clone(existing).(*{{.MessageAlias}}.{{.Kind}})
+	if r.UpdateMask != nil && len(r.UpdateMask.GetPaths()) > 0 {
+		applyUpdateMask(resource, r.{{.Kind}}, r.UpdateMask.GetPaths())
+	} else {
+		proto.Merge(resource, r.{{.Kind}})
+	}
+	resource.Path = r.Path
+	if err := s.Storage.Put(r.Path, resource); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+{{end}}
+{{end}}
+
+{{if .Methods.Delete}}
+{{if .Methods.Delete.LongRunning}}
+// Delete{{.Kind}} starts the delete asynchronously behind a
+// google.longrunning.Operation.
+func (s *{{.Kind}}Server) Delete{{.Kind}}(ctx context.Context, r *{{.MessageAlias}}.Delete{{.Kind}}Request) (*longrunning.Operation, error) {
+	return s.Operations.Start(ctx, nil, func(context.Context) (proto.Message, error) {
+		if err := s.Storage.Delete(r.Path); err != nil {
+			return nil, err
+		}
+		return &emptypb.Empty{}, nil
+	})
+}
+{{else}}
+func (s *{{.Kind}}Server) Delete{{.Kind}}(_ context.Context, r *{{.MessageAlias}}.Delete{{.Kind}}Request) (*emptypb.Empty, error) {
+	if err := s.Storage.Delete(r.Path); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+{{end}}
+{{end}}
+
+{{if .Methods.Apply}}
+{{if .Methods.Apply.LongRunning}}
+// Apply{{.Kind}} starts the write asynchronously behind a
+// google.longrunning.Operation.
+func (s *{{.Kind}}Server) Apply{{.Kind}}(ctx context.Context, r *{{.MessageAlias}}.Apply{{.Kind}}Request) (*longrunning.Operation, error) {
+	resource := proto.Clone(r.{{.Kind}}).(*{{.MessageAlias}}.{{.Kind}})
+	resource.Path = r.Path
+	return s.Operations.Start(ctx, nil, func(context.Context) (proto.Message, error) {
+		if err := s.Storage.Put(r.Path, resource); err != nil {
+			return nil, err
+		}
+		return resource, nil
+	})
+}
+{{else}}
+func (s *{{.Kind}}Server) Apply{{.Kind}}(_ context.Context, r *{{.MessageAlias}}.Apply{{.Kind}}Request) (*{{.MessageAlias}}.{{.Kind}}, error) {
+	resource := proto.Clone(r.{{.Kind}}).(*{{.MessageAlias}}.{{.Kind}})
+	resource.Path = r.Path
+	if err := s.Storage.Put(r.Path, resource); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+{{end}}
+{{end}}
+
+{{if .Methods.List}}
+// List{{.Kind}} applies r.Filter and r.OrderBy over every {{.Kind}}
+// under r.Parent before paginating, so ordering and filtering stay
+// correct across pages.
+func (s *{{.Kind}}Server) List{{.Kind}}(_ context.Context, r *{{.MessageAlias}}.List{{.Kind}}Request) (*{{.MessageAlias}}.List{{.Kind}}Response, error) {
+	all, _, err := s.Storage.List(r.Parent, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	matched, err := filterAndOrder{{.Kind}}(all, r.Filter, r.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+	page, nextPageToken := paginate{{.Kind}}(matched, r.PageToken, r.MaxPageSize)
+	results := make([]*{{.MessageAlias}}.{{.Kind}}, len(page))
+	for i, msg := range page {
+		results[i] = filter.MaskFilter(msg, r.ReadMask).(*{{.MessageAlias}}.{{.Kind}})
+	}
+	return &{{.MessageAlias}}.List{{.Kind}}Response{Results: results, NextPageToken: nextPageToken}, nil
+}
+{{end}}
+
+{{if .Methods.GlobalList}}
+// GlobalList{{.Kind}} lists the {{.Kind}} resources directly under
+// r.Path through Storage.List, then filters, orders and masks them like
+// List{{.Kind}}. Resources stored under other parents are not matched.
+func (s *{{.Kind}}Server) GlobalList{{.Kind}}(_ context.Context, r *{{.MessageAlias}}.GlobalList{{.Kind}}Request) (*{{.MessageAlias}}.GlobalList{{.Kind}}Response, error) {
+	all, _, err := s.Storage.List(r.Path, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	matched, err := filterAndOrder{{.Kind}}(all, r.Filter, r.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+	page, nextPageToken := paginate{{.Kind}}(matched, r.PageToken, 0)
+	results := make([]*{{.MessageAlias}}.{{.Kind}}, len(page))
+	for i, msg := range page {
+		results[i] = filter.MaskFilter(msg, r.ReadMask).(*{{.MessageAlias}}.{{.Kind}})
+	}
+	return &{{.MessageAlias}}.GlobalList{{.Kind}}Response{Results: results, NextPageToken: nextPageToken}, nil
+}
+{{end}}
+
+{{if or .Methods.List .Methods.GlobalList}}
+// filterAndOrder{{.Kind}} applies the AIP-160 filter expr and the
+// AIP-132 order_by expression orderBy to all, returning the matching
+// subset in order.
+func filterAndOrder{{.Kind}}(all []proto.Message, expr, orderBy string) ([]proto.Message, error) {
+	parsed, err := filter.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	matched := make([]proto.Message, 0, len(all))
+	for _, msg := range all {
+		ok, err := parsed.Evaluate(msg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to evaluate filter: %w", err)
+		}
+		if ok {
+			matched = append(matched, msg)
+		}
+	}
+	if err := filter.OrderBy(matched, orderBy); err != nil {
+		return nil, fmt.Errorf("invalid order_by: %w", err)
+	}
+	return matched, nil
+}
+
+// paginate{{.Kind}} slices matched to the page starting after
+// pageToken (a {{.Kind}} path), so List{{.Kind}}/GlobalList{{.Kind}}
+// can apply filtering and ordering over the full candidate set before
+// it's paginated. pageSize <= 0 means no limit.
+func paginate{{.Kind}}(matched []proto.Message, pageToken string, pageSize int32) ([]proto.Message, string) {
+	start := 0
+	if pageToken != "" {
+		for i, msg := range matched {
+			if pathOf(msg) == pageToken {
+				start = i + 1
+				break
+			}
+		}
+	}
+	end := len(matched)
+	if pageSize > 0 && start+int(pageSize) < end {
+		end = start + int(pageSize)
+	}
+	nextPageToken := ""
+	if end < len(matched) {
+		nextPageToken = pathOf(matched[end-1])
+	}
+	return matched[start:end], nextPageToken
+}
+
+// pathOf returns msg's "path" field, used as the resume cursor for
+// pagination.
+func pathOf(msg proto.Message) string {
+	fd := msg.ProtoReflect().Descriptor().Fields().ByName("path")
+	if fd == nil {
+		return ""
+	}
+	return msg.ProtoReflect().Get(fd).String()
+}
+{{end}}
+
+// applyUpdateMask copies only the fields named in paths from src onto
+// dst, so an update can be narrowed to a subset of fields.
+func applyUpdateMask(dst, src proto.Message, paths []string) {
+	dstReflect, srcReflect := dst.ProtoReflect(), src.ProtoReflect()
+	for _, path := range paths {
+		fd := dstReflect.Descriptor().Fields().ByName(protoreflect.Name(path))
+		if fd == nil {
+			continue
+		}
+		dstReflect.Set(fd, srcReflect.Get(fd))
+	}
+}
+`